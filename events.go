@@ -0,0 +1,221 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// watchPodEvents builds a Pod informer and, depending on eventAPI/compare,
+// one or both Event informers, correlating them so that Event updates
+// regarding a Pod we know about are reported as soon as they arrive.
+//
+// When compare is true, both the corev1 and events.k8s.io/v1 APIs are
+// watched concurrently and every update is paired with the latest update
+// from the other API regarding the same object, and the pair is handed to
+// reporter as a side-by-side comparison.
+func watchPodEvents(clientset *kubernetes.Clientset, namespace string, eventAPI string, compare bool, reporter Reporter, stopCh chan struct{}) {
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, resyncPeriod, informers.WithNamespace(namespace))
+
+	podInformer := factory.Core().V1().Pods().Informer()
+
+	var mu sync.Mutex
+	knownPods := map[string]time.Time{}
+
+	podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			pod := obj.(*apiv1.Pod)
+			mu.Lock()
+			knownPods[pod.Name] = time.Now()
+			mu.Unlock()
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			pod := newObj.(*apiv1.Pod)
+			mu.Lock()
+			if _, seen := knownPods[pod.Name]; !seen {
+				knownPods[pod.Name] = time.Now()
+			}
+			mu.Unlock()
+		},
+		DeleteFunc: func(obj interface{}) {
+			if pod, ok := obj.(*apiv1.Pod); ok {
+				mu.Lock()
+				delete(knownPods, pod.Name)
+				mu.Unlock()
+			}
+		},
+	})
+
+	switch {
+	case compare:
+		cmp := newComparator(reporter)
+		eventsInformer := factory.Events().V1().Events().Informer()
+		eventsInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { cmp.observeEventsV1(obj.(*eventsv1.Event), &mu, knownPods) },
+			UpdateFunc: func(oldObj, newObj interface{}) { cmp.observeEventsV1(newObj.(*eventsv1.Event), &mu, knownPods) },
+		})
+		coreInformer := factory.Core().V1().Events().Informer()
+		coreInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { cmp.observeCoreV1(obj.(*apiv1.Event), &mu, knownPods) },
+			UpdateFunc: func(oldObj, newObj interface{}) { cmp.observeCoreV1(newObj.(*apiv1.Event), &mu, knownPods) },
+		})
+	case eventAPI == "core":
+		coreInformer := factory.Core().V1().Events().Informer()
+		coreInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { reportCoreV1Event(obj.(*apiv1.Event), &mu, knownPods, reporter) },
+			UpdateFunc: func(oldObj, newObj interface{}) { reportCoreV1Event(newObj.(*apiv1.Event), &mu, knownPods, reporter) },
+		})
+	default:
+		eventsInformer := factory.Events().V1().Events().Informer()
+		eventsInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { reportEventsV1(obj.(*eventsv1.Event), &mu, knownPods, reporter) },
+			UpdateFunc: func(oldObj, newObj interface{}) { reportEventsV1(newObj.(*eventsv1.Event), &mu, knownPods, reporter) },
+		})
+	}
+
+	factory.Start(stopCh)
+	factory.WaitForCacheSync(stopCh)
+}
+
+// isTrackedPod reports whether name is a Pod we've observed via the Pod
+// informer, guarding access to the shared knownPods map.
+func isTrackedPod(name string, mu *sync.Mutex, knownPods map[string]time.Time) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	_, tracked := knownPods[name]
+	return tracked
+}
+
+// podStartTime returns when we first observed the named Pod, used to derive
+// the time-to-first-nonzero-count metric.
+func podStartTime(name string, mu *sync.Mutex, knownPods map[string]time.Time) time.Time {
+	mu.Lock()
+	defer mu.Unlock()
+	return knownPods[name]
+}
+
+// reportEventsV1 turns an events.k8s.io/v1 Event that regards a Pod we're
+// tracking into an Observation and hands it to reporter.
+func reportEventsV1(ev *eventsv1.Event, mu *sync.Mutex, knownPods map[string]time.Time, reporter Reporter) {
+	if ev.Regarding.Kind != "Pod" || !isTrackedPod(ev.Regarding.Name, mu, knownPods) {
+		return
+	}
+
+	obs := Observation{
+		PodName:             ev.Regarding.Name,
+		EventUID:            ev.UID,
+		RegardingUID:        ev.Regarding.UID,
+		Count:               ev.DeprecatedCount,
+		ReportingController: ev.ReportingController,
+		BugTriggered:        ev.DeprecatedCount == 0,
+		PodStartTime:        podStartTime(ev.Regarding.Name, mu, knownPods),
+	}
+	if ev.Series != nil {
+		obs.SeriesCount = ev.Series.Count
+		obs.SeriesLastObservedTime = ev.Series.LastObservedTime.Time
+	}
+	reporter.Report(obs)
+}
+
+// reportCoreV1Event turns a corev1 Event that regards a Pod we're tracking
+// into an Observation and hands it to reporter.
+func reportCoreV1Event(ev *apiv1.Event, mu *sync.Mutex, knownPods map[string]time.Time, reporter Reporter) {
+	if ev.InvolvedObject.Kind != "Pod" || !isTrackedPod(ev.InvolvedObject.Name, mu, knownPods) {
+		return
+	}
+
+	obs := Observation{
+		PodName:             ev.InvolvedObject.Name,
+		EventUID:            ev.UID,
+		RegardingUID:        ev.InvolvedObject.UID,
+		Count:               ev.Count,
+		ReportingController: ev.ReportingController,
+		BugTriggered:        ev.Count == 0,
+		PodStartTime:        podStartTime(ev.InvolvedObject.Name, mu, knownPods),
+	}
+	if ev.Series != nil {
+		obs.SeriesCount = ev.Series.Count
+		obs.SeriesLastObservedTime = ev.Series.LastObservedTime.Time
+	}
+	reporter.Report(obs)
+}
+
+// comparator tracks the latest Observation seen on each Event API, keyed by
+// the UID of the object the event regards, and hands both sides to reporter
+// once it has seen an update from each.
+type comparator struct {
+	mu       sync.Mutex
+	core     map[types.UID]Observation
+	events   map[types.UID]Observation
+	reporter Reporter
+}
+
+func newComparator(reporter Reporter) *comparator {
+	return &comparator{
+		core:     map[types.UID]Observation{},
+		events:   map[types.UID]Observation{},
+		reporter: reporter,
+	}
+}
+
+func (c *comparator) observeEventsV1(ev *eventsv1.Event, mu *sync.Mutex, knownPods map[string]time.Time) {
+	if ev.Regarding.Kind != "Pod" || !isTrackedPod(ev.Regarding.Name, mu, knownPods) {
+		return
+	}
+
+	obs := Observation{
+		PodName:             ev.Regarding.Name,
+		EventUID:            ev.UID,
+		RegardingUID:        ev.Regarding.UID,
+		Count:               ev.DeprecatedCount,
+		ReportingController: ev.ReportingController,
+		BugTriggered:        ev.DeprecatedCount == 0,
+		PodStartTime:        podStartTime(ev.Regarding.Name, mu, knownPods),
+	}
+	if ev.Series != nil {
+		obs.SeriesCount = ev.Series.Count
+		obs.SeriesLastObservedTime = ev.Series.LastObservedTime.Time
+	}
+
+	c.mu.Lock()
+	c.events[ev.Regarding.UID] = obs
+	other, ok := c.core[ev.Regarding.UID]
+	c.mu.Unlock()
+	if ok {
+		c.reporter.ReportComparison(other, obs)
+	}
+}
+
+func (c *comparator) observeCoreV1(ev *apiv1.Event, mu *sync.Mutex, knownPods map[string]time.Time) {
+	if ev.InvolvedObject.Kind != "Pod" || !isTrackedPod(ev.InvolvedObject.Name, mu, knownPods) {
+		return
+	}
+
+	obs := Observation{
+		PodName:             ev.InvolvedObject.Name,
+		EventUID:            ev.UID,
+		RegardingUID:        ev.InvolvedObject.UID,
+		Count:               ev.Count,
+		ReportingController: ev.ReportingController,
+		BugTriggered:        ev.Count == 0,
+		PodStartTime:        podStartTime(ev.InvolvedObject.Name, mu, knownPods),
+	}
+	if ev.Series != nil {
+		obs.SeriesCount = ev.Series.Count
+		obs.SeriesLastObservedTime = ev.Series.LastObservedTime.Time
+	}
+
+	c.mu.Lock()
+	c.core[ev.InvolvedObject.UID] = obs
+	other, ok := c.events[ev.InvolvedObject.UID]
+	c.mu.Unlock()
+	if ok {
+		c.reporter.ReportComparison(obs, other)
+	}
+}
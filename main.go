@@ -10,7 +10,6 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/kubernetes"
 	v1 "k8s.io/client-go/kubernetes/typed/apps/v1"
 	"k8s.io/client-go/tools/clientcmd"
@@ -22,61 +21,111 @@ import (
 	"time"
 )
 
+// resyncPeriod controls how often the informers replay their full cache
+// through the event handlers, independent of real API server changes.
+const resyncPeriod = 30 * time.Second
+
+var (
+	eventAPI     = flag.String("event-api", "events", `which Events API to watch when not in -compare mode: "core" or "events"`)
+	compare      = flag.Bool("compare", false, "watch both the corev1 and events.k8s.io/v1 Event APIs concurrently and report side-by-side diffs (via -reporter) for events regarding the same object")
+	reporterKind = flag.String("reporter", "console", `how to surface observations: "console", "json", or "prometheus"`)
+	metricsAddr  = flag.String("metrics-addr", ":9090", "address to serve Prometheus metrics on when -reporter=prometheus")
+
+	scenarioName  = flag.String("scenario", "unschedulable-memory", "workload preset to reproduce: unschedulable-memory, image-pull-backoff, or crashloop")
+	namespaceFlag = flag.String("namespace", apiv1.NamespaceDefault, "namespace to create the reproducer Deployment in")
+	replicas      = flag.Int("replicas", 2, "number of replicas in the reproducer Deployment")
+	image         = flag.String("image", "", "container image to use (overrides the -scenario default)")
+	cpuRequest    = flag.String("cpu-request", "", "container CPU request (overrides the -scenario default)")
+	memoryRequest = flag.String("memory-request", "", "container memory request (overrides the -scenario default)")
+)
+
 const deploymentName string = "event-count-issue-reproducer"
 
-var deployment = &appsv1.Deployment{
-	ObjectMeta: metav1.ObjectMeta{
-		Name: deploymentName,
-	},
-	Spec: appsv1.DeploymentSpec{
-		Replicas: int32Ptr(2),
-		Selector: &metav1.LabelSelector{
-			MatchLabels: map[string]string{
-				"app": "demo",
+// scaleQuantity returns q scaled by ratio, preserving q's format, so a
+// Limit can track whatever Request it's derived from even when the
+// request came from a flag override rather than the scenario default.
+func scaleQuantity(q resource.Quantity, ratio int64) resource.Quantity {
+	return *resource.NewMilliQuantity(q.MilliValue()*ratio, q.Format)
+}
+
+// buildDeployment assembles the reproducer Deployment for scenario, applying
+// any non-empty flag overrides on top of the scenario's defaults.
+func buildDeployment(namespace string, scenario Scenario) *appsv1.Deployment {
+	containerImage := scenario.Image
+	if *image != "" {
+		containerImage = *image
+	}
+	memReqStr := scenario.MemoryRequest
+	if *memoryRequest != "" {
+		memReqStr = *memoryRequest
+	}
+	cpuReqStr := scenario.CPURequest
+	if *cpuRequest != "" {
+		cpuReqStr = *cpuRequest
+	}
+
+	memReq := resource.MustParse(memReqStr)
+	cpuReq := resource.MustParse(cpuReqStr)
+	memLimit := scaleQuantity(memReq, scenario.MemoryLimitRatio)
+	cpuLimit := scaleQuantity(cpuReq, scenario.CPULimitRatio)
+
+	container := apiv1.Container{
+		Name:    "web",
+		Image:   containerImage,
+		Command: scenario.Command,
+		Ports: []apiv1.ContainerPort{
+			{
+				Name:          "http",
+				Protocol:      apiv1.ProtocolTCP,
+				ContainerPort: 80,
+			},
+		},
+		Resources: apiv1.ResourceRequirements{
+			Requests: apiv1.ResourceList{
+				apiv1.ResourceMemory: memReq,
+				apiv1.ResourceCPU:    cpuReq,
+			},
+			Limits: apiv1.ResourceList{
+				apiv1.ResourceMemory: memLimit,
+				apiv1.ResourceCPU:    cpuLimit,
 			},
 		},
-		Template: apiv1.PodTemplateSpec{
-			ObjectMeta: metav1.ObjectMeta{
-				Labels: map[string]string{
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deploymentName,
+			Namespace: namespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Ptr(int32(*replicas)),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
 					"app": "demo",
 				},
 			},
-			Spec: apiv1.PodSpec{
-				Containers: []apiv1.Container{
-					{
-						Name:  "web",
-						Image: "nginx:1.12",
-						Ports: []apiv1.ContainerPort{
-							{
-								Name:          "http",
-								Protocol:      apiv1.ProtocolTCP,
-								ContainerPort: 80,
-							},
-						},
-						Resources: apiv1.ResourceRequirements{
-							Requests: apiv1.ResourceList{
-								apiv1.ResourceMemory: resource.MustParse("32Gi"),
-								apiv1.ResourceCPU:    resource.MustParse("500m"),
-							},
-							Limits: apiv1.ResourceList{
-								apiv1.ResourceMemory: resource.MustParse("64Gi"),
-								apiv1.ResourceCPU:    resource.MustParse("2000m"),
-							},
-						},
+			Template: apiv1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app": "demo",
 					},
 				},
+				Spec: apiv1.PodSpec{
+					Containers: []apiv1.Container{container},
+				},
 			},
 		},
-	},
+	}
 }
 
 func main() {
 	clientset := getClientset()
-	namespace := apiv1.NamespaceDefault
+
+	namespace := *namespaceFlag
+	scenario := scenarioOrExit(*scenarioName)
+	deployment := buildDeployment(namespace, scenario)
 
 	deploymentsClient := clientset.AppsV1().Deployments(namespace)
-	podsClient := clientset.CoreV1().Pods(namespace)
-	eventsClient := clientset.EventsV1()
 
 	// Create Deployment
 	fmt.Println("Creating deployment...")
@@ -90,54 +139,20 @@ func main() {
 	// Allow OS interrupt to delete deployment before exiting
 	c := make(chan os.Signal)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	stopCh := make(chan struct{})
 	go func() {
 		<-c
+		close(stopCh)
 		cleanupAndExit(deploymentsClient)
 		os.Exit(0)
 	}()
 
-	// Loop indefinetly, periodically checking for pod events
-	for {
-		// Get pods
-		podList, err := podsClient.List(context.TODO(), metav1.ListOptions{})
-		if err != nil {
-			fmt.Printf("Error listing pods: %s", err)
-			cleanupAndExit(deploymentsClient)
-		}
-
-		for _, pod := range podList.Items {
-			// Get pod events
-			selector, err := fields.ParseSelector(fmt.Sprintf("regarding.name=%s", pod.Name))
-			if err != nil {
-				fmt.Printf("failed to parse field selector: %s", err)
-				cleanupAndExit(deploymentsClient)
-			}
-			events, err := eventsClient.Events(namespace).List(context.TODO(), metav1.ListOptions{FieldSelector: selector.String()})
-			if err != nil {
-				fmt.Printf("failed to get events: %s", err)
-				cleanupAndExit(deploymentsClient)
-			}
-
-			for _, ev := range events.Items {
-				if ev.Regarding.Kind != "Pod" {
-					continue
-				}
-
-				if ev.DeprecatedCount == 0 {
-					outputMsg := fmt.Sprintf("Issue is occuring - Event count is 0 for pod %s. ", pod.Name)
-					if ev.Series == nil {
-						outputMsg = outputMsg + "Event series is also nil"
-					}
-					fmt.Println(outputMsg)
-				} else {
-					fmt.Printf("Issue is no longer occuring - Event count is %d  for pod %s \n", ev.DeprecatedCount, pod.Name)
-				}
-			}
-		}
-
-		time.Sleep(time.Second * 1)
-	}
+	// Watch Pods and Events instead of polling, so we can react the moment
+	// the API server mutates an Event's count/series - precisely when the
+	// count-zero bug manifests.
+	watchPodEvents(clientset, namespace, *eventAPI, *compare, newReporter(*reporterKind, *metricsAddr), stopCh)
 
+	<-stopCh
 }
 
 func cleanupAndExit(deploymentsClient v1.DeploymentInterface) {
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Observation is a single point-in-time read of an Event's count/series
+// state, emitted once per Add/Update we see on the Event informers.
+type Observation struct {
+	PodName                string    `json:"pod_name"`
+	EventUID               types.UID `json:"event_uid"`
+	RegardingUID           types.UID `json:"regarding_uid"`
+	Count                  int32     `json:"count"`
+	SeriesCount            int32     `json:"series_count"`
+	SeriesLastObservedTime time.Time `json:"series_last_observed_time"`
+	ReportingController    string    `json:"reporting_controller"`
+	BugTriggered           bool      `json:"bug_triggered"`
+	PodStartTime           time.Time `json:"-"`
+}
+
+// Reporter consumes Observations. Implementations decide how (or whether)
+// to surface them to the operator.
+type Reporter interface {
+	Report(obs Observation)
+
+	// ReportComparison surfaces a -compare mode pairing: the latest corev1
+	// and events.k8s.io/v1 observations regarding the same object, keyed by
+	// the regarding object's UID. When several Events regard that object,
+	// the two sides may come from different Events.
+	ReportComparison(core, events Observation)
+}
+
+// newReporter builds the Reporter selected by the -reporter flag.
+func newReporter(kind, metricsAddr string) Reporter {
+	switch kind {
+	case "json":
+		return newJSONReporter(os.Stdout)
+	case "prometheus":
+		return newPrometheusReporter(metricsAddr)
+	default:
+		return consoleReporter{}
+	}
+}
+
+// consoleReporter preserves the original fmt.Println-based diagnostic.
+type consoleReporter struct{}
+
+func (consoleReporter) Report(obs Observation) {
+	if obs.BugTriggered {
+		outputMsg := fmt.Sprintf("Issue is occuring - Event count is 0 for pod %s. ", obs.PodName)
+		if obs.SeriesCount == 0 && obs.SeriesLastObservedTime.IsZero() {
+			outputMsg = outputMsg + "Event series is also nil"
+		}
+		fmt.Println(outputMsg)
+	} else {
+		fmt.Printf("Issue is no longer occuring - Event count is %d  for pod %s \n", obs.Count, obs.PodName)
+	}
+}
+
+// ReportComparison prints a side-by-side diff of the latest corev1 and
+// events.k8s.io/v1 observations regarding the same object.
+func (consoleReporter) ReportComparison(core, events Observation) {
+	fmt.Printf("Comparing event for pod %s (regarding.uid=%s):\n", events.PodName, events.RegardingUID)
+	fmt.Printf("  corev1:   count=%d seriesCount=%d seriesLastObserved=%s reportingController=%s\n",
+		core.Count, core.SeriesCount, core.SeriesLastObservedTime, core.ReportingController)
+	fmt.Printf("  eventsv1: count=%d seriesCount=%d seriesLastObserved=%s reportingController=%s\n",
+		events.Count, events.SeriesCount, events.SeriesLastObservedTime, events.ReportingController)
+	if core.Count != events.Count {
+		fmt.Printf("  MISMATCH: corev1 count=%d but eventsv1 DeprecatedCount=%d\n", core.Count, events.Count)
+	}
+}
+
+// jsonReporter emits one JSON object per Observation, suitable for CI or
+// long-running soak tests to consume.
+type jsonReporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func newJSONReporter(w *os.File) *jsonReporter {
+	return &jsonReporter{enc: json.NewEncoder(w)}
+}
+
+func (r *jsonReporter) Report(obs Observation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.enc.Encode(obs); err != nil {
+		fmt.Printf("failed to encode observation: %s\n", err)
+	}
+}
+
+// comparisonRecord is the JSON-lines shape emitted for a -compare pairing.
+type comparisonRecord struct {
+	Core     Observation `json:"core"`
+	Events   Observation `json:"events"`
+	Mismatch bool        `json:"mismatch"`
+}
+
+func (r *jsonReporter) ReportComparison(core, events Observation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rec := comparisonRecord{Core: core, Events: events, Mismatch: core.Count != events.Count}
+	if err := r.enc.Encode(rec); err != nil {
+		fmt.Printf("failed to encode comparison: %s\n", err)
+	}
+}
+
+// prometheusReporter serves reproducer_events_total, split by whether the
+// count-zero bug was triggered, and a histogram of how long it took each
+// pod to go from first observed to a nonzero Event count.
+type prometheusReporter struct {
+	mu       sync.Mutex
+	recorded map[string]bool
+
+	eventsTotal     *prometheus.CounterVec
+	timeToNonZero   prometheus.Histogram
+	compareMismatch prometheus.Counter
+}
+
+func newPrometheusReporter(addr string) *prometheusReporter {
+	r := &prometheusReporter{
+		recorded: map[string]bool{},
+		eventsTotal: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "reproducer_events_total",
+			Help: "Total Event observations, labeled by whether the count-zero bug was triggered.",
+		}, []string{"count_zero"}),
+		timeToNonZero: promauto.NewHistogram(prometheus.HistogramOpts{
+			Name:    "reproducer_time_to_first_nonzero_count_seconds",
+			Help:    "Time between a Pod first being observed and its Event count first reading nonzero.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		compareMismatch: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "reproducer_compare_mismatch_total",
+			Help: "Total -compare pairings where the corev1 and events.k8s.io/v1 counts disagreed.",
+		}),
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Printf("prometheus metrics server exited: %s", err)
+		}
+	}()
+	fmt.Printf("Serving Prometheus metrics on %s/metrics\n", addr)
+
+	return r
+}
+
+func (r *prometheusReporter) Report(obs Observation) {
+	countZero := "false"
+	if obs.BugTriggered {
+		countZero = "true"
+	}
+	r.eventsTotal.WithLabelValues(countZero).Inc()
+
+	if obs.BugTriggered || obs.PodStartTime.IsZero() {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.recorded[obs.PodName] {
+		return
+	}
+	r.recorded[obs.PodName] = true
+	r.timeToNonZero.Observe(time.Since(obs.PodStartTime).Seconds())
+}
+
+// ReportComparison records both sides of a -compare pairing as ordinary
+// Observations, plus a dedicated counter for count mismatches between them.
+func (r *prometheusReporter) ReportComparison(core, events Observation) {
+	r.Report(core)
+	r.Report(events)
+	if core.Count != events.Count {
+		r.compareMismatch.Inc()
+	}
+}
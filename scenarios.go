@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// Scenario is a workload shape known to force a specific Event reason, so
+// reproducer runs can determine whether the count-zero bug is universal
+// across Event reasons or specific to one of them.
+type Scenario struct {
+	Image         string
+	Command       []string
+	MemoryRequest string
+	CPURequest    string
+
+	// MemoryLimitRatio and CPULimitRatio scale the effective request (the
+	// scenario default, or a -memory-request/-cpu-request override) up to a
+	// Limit, so an overridden request can never exceed the Limit and get
+	// rejected by the API server.
+	MemoryLimitRatio int64
+	CPULimitRatio    int64
+}
+
+var scenarios = map[string]Scenario{
+	// unschedulable-memory requests far more memory than any node has,
+	// forcing repeated FailedScheduling events. This is the reproducer's
+	// original behavior.
+	"unschedulable-memory": {
+		Image:            "nginx:1.12",
+		MemoryRequest:    "32Gi",
+		MemoryLimitRatio: 2,
+		CPURequest:       "500m",
+		CPULimitRatio:    4,
+	},
+	// image-pull-backoff references a tag that doesn't exist, forcing
+	// repeated Failed/BackOff image pull events.
+	"image-pull-backoff": {
+		Image:            "nginx:this-tag-does-not-exist",
+		MemoryRequest:    "64Mi",
+		MemoryLimitRatio: 2,
+		CPURequest:       "100m",
+		CPULimitRatio:    2,
+	},
+	// crashloop runs a command that exits nonzero immediately, forcing
+	// repeated BackOff events.
+	"crashloop": {
+		Image:            "nginx:1.12",
+		Command:          []string{"sh", "-c", "exit 1"},
+		MemoryRequest:    "64Mi",
+		MemoryLimitRatio: 2,
+		CPURequest:       "100m",
+		CPULimitRatio:    2,
+	},
+}
+
+// scenarioOrExit looks up name in scenarios, exiting with an error message
+// if it isn't a known preset.
+func scenarioOrExit(name string) Scenario {
+	s, ok := scenarios[name]
+	if !ok {
+		fmt.Printf("unknown scenario %q\n", name)
+		os.Exit(1)
+	}
+	return s
+}